@@ -0,0 +1,21 @@
+package attestations
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	bucketCount = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "aggregation_pool_bucket_count",
+		Help: "Number of (slot, committeeIndex, attDataRoot) buckets currently held by the aggregation pool.",
+	})
+	avgBucketDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "aggregation_pool_avg_bucket_depth",
+		Help: "Average number of unaggregated attestations per bucket in the aggregation pool.",
+	})
+	sweepEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "aggregation_pool_sweep_evictions_total",
+		Help: "Total number of buckets evicted by the aggregation pool sweeper for being older than slots_per_epoch.",
+	})
+)
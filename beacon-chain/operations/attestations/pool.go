@@ -0,0 +1,177 @@
+// Package attestations provides an aggregation pool that indexes
+// unaggregated attestations by slot, committee index, and AttestationData
+// root, and materializes aggregates on demand, bounding work per call to a
+// single bucket rather than the whole pool.
+package attestations
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	aggregation "github.com/prysmaticlabs/prysm/shared/aggregation/attestations"
+)
+
+// bucketKey indexes a set of unaggregated attestations that can legally be
+// combined: they share a slot, committee index, and AttestationData root.
+type bucketKey struct {
+	slot           uint64
+	committeeIndex uint64
+	attDataRoot    [32]byte
+}
+
+// bucket holds the unaggregated attestations for a bucketKey, along with the
+// slot they belong to so the sweeper can evict it once it expires.
+type bucket struct {
+	atts []*ethpb.Attestation
+	slot uint64
+}
+
+// AggregationPool indexes unaggregated attestations into per-(slot,
+// committeeIndex, attDataRoot) buckets. GetAggregate runs MaxCover only
+// within the addressed bucket, so the cost of a call is bounded by bucket
+// size rather than total pool size. The pool is not wired into the gossip
+// handler or any RPC. A background sweeper evicts
+// buckets whose slot has fallen more than slotsPerEpoch slots behind the
+// current slot.
+type AggregationPool struct {
+	lock           sync.RWMutex
+	buckets        map[bucketKey]*bucket
+	slotsPerEpoch  uint64
+	genesisTime    time.Time
+	secondsPerSlot uint64
+
+	sweepInterval time.Duration
+	stop          chan struct{}
+	done          chan struct{}
+}
+
+// NewAggregationPool returns an AggregationPool that evicts buckets older
+// than slotsPerEpoch slots. genesisTime and secondsPerSlot are used to
+// derive the current slot for eviction purposes; sweepInterval controls how
+// often the sweeper scans for expired buckets.
+func NewAggregationPool(genesisTime time.Time, secondsPerSlot, slotsPerEpoch uint64, sweepInterval time.Duration) *AggregationPool {
+	return &AggregationPool{
+		buckets:        make(map[bucketKey]*bucket),
+		slotsPerEpoch:  slotsPerEpoch,
+		genesisTime:    genesisTime,
+		secondsPerSlot: secondsPerSlot,
+		sweepInterval:  sweepInterval,
+	}
+}
+
+// Start launches the background sweeper. It is a no-op if already started.
+func (p *AggregationPool) Start(ctx context.Context) {
+	if p.stop != nil {
+		return
+	}
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.sweeper(ctx)
+}
+
+// Stop halts the background sweeper and blocks until it has exited.
+func (p *AggregationPool) Stop() {
+	if p.stop == nil {
+		return
+	}
+	close(p.stop)
+	<-p.done
+	p.stop = nil
+	p.done = nil
+}
+
+// AddAttestation indexes att into its bucket, keyed by its slot, committee
+// index, and AttestationData root.
+func (p *AggregationPool) AddAttestation(att *ethpb.Attestation) error {
+	if att == nil || att.Data == nil {
+		return errors.New("nil attestation")
+	}
+	key, err := bucketKeyFromData(att.Data)
+	if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	b, ok := p.buckets[key]
+	if !ok {
+		b = &bucket{slot: uint64(att.Data.Slot)}
+		p.buckets[key] = b
+	}
+	b.atts = append(b.atts, att)
+	bucketCount.Set(float64(len(p.buckets)))
+	avgBucketDepth.Set(p.averageDepth())
+	return nil
+}
+
+// GetAggregate runs MaxCover over the bucket addressed by (slot,
+// committeeIndex, attDataRoot) and returns the best aggregate found. It does
+// not touch any other bucket, bounding the work of a single call to the size
+// of that bucket.
+func (p *AggregationPool) GetAggregate(slot, committeeIndex uint64, attDataRoot [32]byte) (*ethpb.Attestation, error) {
+	key := bucketKey{slot: slot, committeeIndex: committeeIndex, attDataRoot: attDataRoot}
+
+	p.lock.RLock()
+	b, ok := p.buckets[key]
+	var atts []*ethpb.Attestation
+	if ok {
+		atts = make([]*ethpb.Attestation, len(b.atts))
+		copy(atts, b.atts)
+	}
+	p.lock.RUnlock()
+
+	if len(atts) == 0 {
+		return nil, errors.New("no attestations for bucket")
+	}
+	aggregated, err := aggregation.MaxCoverAttestationAggregation(atts)
+	if err != nil {
+		return nil, err
+	}
+	best := aggregated[0]
+	for _, att := range aggregated[1:] {
+		if att.AggregationBits.Count() > best.AggregationBits.Count() {
+			best = att
+		}
+	}
+	return best, nil
+}
+
+// bucketKeyFromData derives the bucket key for a given AttestationData.
+func bucketKeyFromData(data *ethpb.AttestationData) (bucketKey, error) {
+	if data == nil {
+		return bucketKey{}, errors.New("nil attestation data")
+	}
+	root, err := data.HashTreeRoot()
+	if err != nil {
+		return bucketKey{}, err
+	}
+	return bucketKey{slot: uint64(data.Slot), committeeIndex: uint64(data.CommitteeIndex), attDataRoot: root}, nil
+}
+
+// currentSlot derives the current slot from genesisTime and secondsPerSlot.
+func (p *AggregationPool) currentSlot() uint64 {
+	if p.secondsPerSlot == 0 {
+		return 0
+	}
+	elapsed := time.Since(p.genesisTime)
+	if elapsed < 0 {
+		return 0
+	}
+	return uint64(elapsed.Seconds()) / p.secondsPerSlot
+}
+
+// averageDepth returns the mean number of attestations per bucket. Callers
+// must hold p.lock.
+func (p *AggregationPool) averageDepth() float64 {
+	if len(p.buckets) == 0 {
+		return 0
+	}
+	total := 0
+	for _, b := range p.buckets {
+		total += len(b.atts)
+	}
+	return float64(total) / float64(len(p.buckets))
+}
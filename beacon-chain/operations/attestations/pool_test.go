@@ -0,0 +1,104 @@
+package attestations
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+// testSignature returns a signature over a real BLS key, since GetAggregate
+// runs MaxCover, which rejects the all-zero (non-point) signature.
+func testSignature() []byte {
+	return bls.RandKey().Sign([]byte("attestation")).Marshal()
+}
+
+func attForSlot(slot, committeeIndex uint64, bit uint64) *ethpb.Attestation {
+	bits := bitfield.NewBitlist(8)
+	bits.SetBitAt(bit, true)
+	return &ethpb.Attestation{
+		AggregationBits: bits,
+		Data:            &ethpb.AttestationData{Slot: slot, CommitteeIndex: committeeIndex},
+		Signature:       testSignature(),
+	}
+}
+
+func TestAggregationPool_AddAndGetAggregate(t *testing.T) {
+	pool := NewAggregationPool(time.Now(), 12, 32, time.Minute)
+
+	att1 := attForSlot(1, 0, 0)
+	att2 := attForSlot(1, 0, 1)
+	require.NoError(t, pool.AddAttestation(att1))
+	require.NoError(t, pool.AddAttestation(att2))
+
+	root, err := att1.Data.HashTreeRoot()
+	require.NoError(t, err)
+
+	agg, err := pool.GetAggregate(1, 0, root)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), agg.AggregationBits.Count())
+}
+
+func TestAggregationPool_GetAggregate_EmptyBucket(t *testing.T) {
+	pool := NewAggregationPool(time.Now(), 12, 32, time.Minute)
+	_, err := pool.GetAggregate(1, 0, [32]byte{})
+	assert.NotNil(t, err)
+}
+
+func TestAggregationPool_ConcurrentAddAndGet(t *testing.T) {
+	pool := NewAggregationPool(time.Now(), 12, 32, time.Minute)
+	data := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0}
+	root, err := data.HashTreeRoot()
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := uint64(0); i < 8; i++ {
+		wg.Add(1)
+		go func(bit uint64) {
+			defer wg.Done()
+			bits := bitfield.NewBitlist(8)
+			bits.SetBitAt(bit, true)
+			att := &ethpb.Attestation{AggregationBits: bits, Data: data, Signature: testSignature()}
+			require.NoError(t, pool.AddAttestation(att))
+			_, _ = pool.GetAggregate(1, 0, root)
+		}(i)
+	}
+	wg.Wait()
+
+	agg, err := pool.GetAggregate(1, 0, root)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(8), agg.AggregationBits.Count())
+}
+
+func TestAggregationPool_Sweep_EvictsOnlyExpiredBuckets(t *testing.T) {
+	pool := NewAggregationPool(time.Now(), 12, 32, time.Minute)
+	require.NoError(t, pool.AddAttestation(attForSlot(0, 0, 0)))
+	require.NoError(t, pool.AddAttestation(attForSlot(100, 0, 0)))
+
+	evicted := pool.sweep(100)
+	assert.Equal(t, 1, evicted)
+	assert.Equal(t, 1, len(pool.buckets))
+}
+
+func TestAggregationPool_Sweep_ToleratesClockSkew(t *testing.T) {
+	pool := NewAggregationPool(time.Now(), 12, 32, time.Minute)
+	require.NoError(t, pool.AddAttestation(attForSlot(50, 0, 0)))
+
+	// currentSlot appears to be behind the bucket's slot (clock skew); the
+	// bucket must not be evicted since it cannot yet be "too old".
+	evicted := pool.sweep(10)
+	assert.Equal(t, 0, evicted)
+	assert.Equal(t, 1, len(pool.buckets))
+}
+
+func TestAggregationPool_StartStop(t *testing.T) {
+	pool := NewAggregationPool(time.Now(), 12, 32, time.Millisecond)
+	pool.Start(context.Background())
+	pool.Stop()
+}
@@ -0,0 +1,53 @@
+package attestations
+
+import (
+	"context"
+	"time"
+)
+
+// sweeper periodically evicts buckets whose slot has fallen more than
+// slotsPerEpoch slots behind the current slot, so the pool does not grow
+// unbounded across epochs.
+func (p *AggregationPool) sweeper(ctx context.Context) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.sweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.sweep(p.currentSlot())
+		case <-p.stop:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sweep evicts every bucket whose slot is more than slotsPerEpoch behind
+// currentSlot. It tolerates clock skew that puts currentSlot slightly behind
+// a bucket's slot by only ever evicting buckets that are strictly too old,
+// never ones that merely appear to be from "the future".
+func (p *AggregationPool) sweep(currentSlot uint64) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	evicted := 0
+	for key, b := range p.buckets {
+		if currentSlot <= b.slot {
+			continue
+		}
+		if currentSlot-b.slot > p.slotsPerEpoch {
+			delete(p.buckets, key)
+			evicted++
+		}
+	}
+	if evicted > 0 {
+		sweepEvictions.Add(float64(evicted))
+	}
+	bucketCount.Set(float64(len(p.buckets)))
+	avgBucketDepth.Set(p.averageDepth())
+	return evicted
+}
@@ -0,0 +1,112 @@
+package attestations
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+// AttestationSet maintains the maximal (non-dominated) attestations inserted
+// into it: an attestation is dominated when another attestation's
+// AggregationBits is a superset of its own. Maximality is maintained
+// incrementally on every Insert, bucketed by AggregationBits popcount: a new
+// candidate is checked for containment only against maximals with a
+// greater-or-equal popcount, and only maximals with a lesser-or-equal
+// popcount are checked for being contained by it. This replaces the
+// end-of-cycle sort+scan that filterContained used to perform, spreading the
+// containment work across inserts instead.
+type AttestationSet struct {
+	maximalsByCount map[uint64][]*ethpb.Attestation
+	size            int
+}
+
+// NewAttestationSet returns an empty AttestationSet.
+func NewAttestationSet() *AttestationSet {
+	return &AttestationSet{maximalsByCount: make(map[uint64][]*ethpb.Attestation)}
+}
+
+// NewAttestationSetFromAttestations returns an AttestationSet containing the
+// maximal attestations among atts.
+func NewAttestationSetFromAttestations(atts []*ethpb.Attestation) (*AttestationSet, error) {
+	set := NewAttestationSet()
+	for _, att := range atts {
+		if err := set.Insert(att); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// Insert adds att to the set, dropping it if it is contained within an
+// existing maximal, and dropping any existing maximals that att contains.
+func (s *AttestationSet) Insert(att *ethpb.Attestation) error {
+	if att == nil || att.AggregationBits == nil {
+		return errors.New("nil attestation")
+	}
+	count := att.AggregationBits.Count()
+
+	// An existing maximal can only dominate att if it has a greater-or-equal
+	// popcount, so only compare against those buckets.
+	for c, bucket := range s.maximalsByCount {
+		if c < count {
+			continue
+		}
+		for _, existing := range bucket {
+			if existing.AggregationBits.Contains(att.AggregationBits) {
+				return nil
+			}
+		}
+	}
+
+	// att can only dominate an existing maximal with a lesser-or-equal
+	// popcount, so only prune those buckets.
+	for c, bucket := range s.maximalsByCount {
+		if c > count {
+			continue
+		}
+		filtered := bucket[:0]
+		for _, existing := range bucket {
+			if att.AggregationBits.Contains(existing.AggregationBits) {
+				s.size--
+				continue
+			}
+			filtered = append(filtered, existing)
+		}
+		if len(filtered) == 0 {
+			delete(s.maximalsByCount, c)
+		} else {
+			s.maximalsByCount[c] = filtered
+		}
+	}
+
+	s.maximalsByCount[count] = append(s.maximalsByCount[count], att)
+	s.size++
+	return nil
+}
+
+// Maximals returns every attestation currently in the set, sorted by
+// popcount descending and then by raw bit pattern, so that callers (and
+// consensus-critical code building on them, such as block packing) see a
+// deterministic order across runs rather than Go's randomized map iteration
+// order.
+func (s *AttestationSet) Maximals() []*ethpb.Attestation {
+	out := make([]*ethpb.Attestation, 0, s.size)
+	for _, bucket := range s.maximalsByCount {
+		out = append(out, bucket...)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		ci, cj := out[i].AggregationBits.Count(), out[j].AggregationBits.Count()
+		if ci != cj {
+			return ci > cj
+		}
+		return bytes.Compare(out[i].AggregationBits.Bytes(), out[j].AggregationBits.Bytes()) < 0
+	})
+	return out
+}
+
+// Len returns the number of maximal attestations currently held.
+func (s *AttestationSet) Len() int {
+	return s.size
+}
@@ -0,0 +1,125 @@
+package attestations
+
+import (
+	"math/rand"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func randomAttestation(r *rand.Rand, bitlistLen uint64) *ethpb.Attestation {
+	bits := bitfield.NewBitlist(bitlistLen)
+	for i := uint64(0); i < bitlistLen; i++ {
+		if r.Intn(2) == 0 {
+			bits.SetBitAt(i, true)
+		}
+	}
+	return &ethpb.Attestation{
+		AggregationBits: bits,
+		Data:            &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0},
+		Signature:       testSignature(),
+	}
+}
+
+// bruteForceMaximals is the O(n^2) oracle: an attestation is maximal iff no
+// other attestation in atts strictly contains it.
+func bruteForceMaximals(atts []*ethpb.Attestation) []*ethpb.Attestation {
+	var maximals []*ethpb.Attestation
+	for i, att := range atts {
+		dominated := false
+		for j, other := range atts {
+			if i == j {
+				continue
+			}
+			if other.AggregationBits.Contains(att.AggregationBits) &&
+				other.AggregationBits.Count() > att.AggregationBits.Count() {
+				dominated = true
+				break
+			}
+			// Equal bitlists: keep only the first occurrence, mirroring
+			// AttestationSet's de-dup behavior.
+			if other.AggregationBits.Count() == att.AggregationBits.Count() &&
+				other.AggregationBits.Xor(att.AggregationBits).Count() == 0 && j < i {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			maximals = append(maximals, att)
+		}
+	}
+	return maximals
+}
+
+func TestAttestationSet_Maximals_MatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		n := 1 + r.Intn(20)
+		atts := make([]*ethpb.Attestation, n)
+		for i := range atts {
+			atts[i] = randomAttestation(r, 8)
+		}
+
+		set, err := NewAttestationSetFromAttestations(atts)
+		require.NoError(t, err)
+
+		want := len(bruteForceMaximals(atts))
+		got := set.Len()
+		assert.Equal(t, want, got)
+
+		for _, m := range set.Maximals() {
+			for _, other := range set.Maximals() {
+				if m == other {
+					continue
+				}
+				assert.Equal(t, false, other.AggregationBits.Contains(m.AggregationBits) &&
+					other.AggregationBits.Count() > m.AggregationBits.Count())
+			}
+		}
+	}
+}
+
+func TestAttestationSet_Insert_DropsContained(t *testing.T) {
+	set := NewAttestationSet()
+	big := attWithBits(&ethpb.AttestationData{Slot: 1}, 8, 0, 1, 2)
+	small := attWithBits(&ethpb.AttestationData{Slot: 1}, 8, 0)
+
+	require.NoError(t, set.Insert(big))
+	require.NoError(t, set.Insert(small))
+
+	assert.Equal(t, 1, set.Len())
+	assert.Equal(t, big, set.Maximals()[0])
+}
+
+func TestAttestationSet_Insert_NewDominatesExisting(t *testing.T) {
+	set := NewAttestationSet()
+	small := attWithBits(&ethpb.AttestationData{Slot: 1}, 8, 0)
+	big := attWithBits(&ethpb.AttestationData{Slot: 1}, 8, 0, 1, 2)
+
+	require.NoError(t, set.Insert(small))
+	require.NoError(t, set.Insert(big))
+
+	assert.Equal(t, 1, set.Len())
+	assert.Equal(t, big, set.Maximals()[0])
+}
+
+func BenchmarkAttestationSet_Insert(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	atts := make([]*ethpb.Attestation, 10000)
+	for i := range atts {
+		atts[i] = randomAttestation(r, 64)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		set := NewAttestationSet()
+		for _, att := range atts {
+			if err := set.Insert(att); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package attestations
+
+import (
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+// BestAggregateOpt configures BestAggregateForData.
+type BestAggregateOpt func(*bestAggregateConfig)
+
+type bestAggregateConfig struct {
+	singles []*ethpb.Attestation
+}
+
+// WithSinglesTopUp tops up every candidate with any compatible singleton
+// votes in singles before comparing bit counts, so that the returned
+// aggregate reflects singles that have not yet been folded into a network
+// aggregate.
+func WithSinglesTopUp(singles []*ethpb.Attestation) BestAggregateOpt {
+	return func(cfg *bestAggregateConfig) {
+		cfg.singles = singles
+	}
+}
+
+// BestAggregateForData returns the single aggregate with the highest
+// AggregationBits.Count() among atts, all of which must share the same
+// AttestationData. A pass of optMaxCoverAttestationAggregation is run first
+// to merge non-overlapping candidates, so the "best" aggregate may combine
+// several inputs. optMaxCoverAttestationAggregation returns merged aggregates
+// before the unmerged remainder, so on a count tie this favors an aggregate
+// produced by the merge pass over an input that was left untouched.
+func BestAggregateForData(atts []*ethpb.Attestation, opts ...BestAggregateOpt) (*ethpb.Attestation, error) {
+	if len(atts) == 0 {
+		return nil, errors.Wrap(ErrInvalidAttestationCount, "no attestations for data")
+	}
+
+	cfg := &bestAggregateConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	candidates := atts
+	if len(cfg.singles) > 0 {
+		pool := NewSinglesPool()
+		for _, single := range cfg.singles {
+			if err := pool.Add(single); err != nil {
+				return nil, err
+			}
+		}
+		toppedUp, err := pool.TopUpAggregates(atts)
+		if err != nil {
+			return nil, err
+		}
+		candidates = toppedUp
+	}
+
+	merged, err := optMaxCoverAttestationAggregation(candidates)
+	if err != nil {
+		return nil, err
+	}
+	if len(merged) == 0 {
+		return nil, errors.Wrap(ErrInvalidAttestationCount, "no attestations for data")
+	}
+
+	// Only replace on a strictly higher count, so that ties keep the earlier
+	// candidate in merged (a merged aggregate, if any were produced).
+	best := merged[0]
+	for _, att := range merged[1:] {
+		if att.AggregationBits.Count() > best.AggregationBits.Count() {
+			best = att
+		}
+	}
+	return best, nil
+}
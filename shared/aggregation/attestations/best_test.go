@@ -0,0 +1,41 @@
+package attestations
+
+import (
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func TestBestAggregateForData_PicksHighestCount(t *testing.T) {
+	data := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0}
+	// small and big overlap at bit 1, so optMaxCoverAttestationAggregation
+	// cannot merge them; both survive as maximals and the highest count wins.
+	small := attWithBits(data, 8, 0, 1)
+	big := attWithBits(data, 8, 1, 2, 3)
+
+	best, err := BestAggregateForData([]*ethpb.Attestation{small, big})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), best.AggregationBits.Count())
+}
+
+func TestBestAggregateForData_NoAttestations(t *testing.T) {
+	_, err := BestAggregateForData(nil)
+	assert.NotNil(t, err)
+}
+
+func TestBestAggregateForData_WithSinglesTopUp(t *testing.T) {
+	data := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0}
+	agg := attWithBits(data, 8, 0, 1)
+	singles := []*ethpb.Attestation{attWithBits(data, 8, 2)}
+
+	withoutTopUp, err := BestAggregateForData([]*ethpb.Attestation{agg})
+	require.NoError(t, err)
+
+	withTopUp, err := BestAggregateForData([]*ethpb.Attestation{agg}, WithSinglesTopUp(singles))
+	require.NoError(t, err)
+
+	assert.Equal(t, true, withTopUp.AggregationBits.Count() >= withoutTopUp.AggregationBits.Count())
+	assert.Equal(t, true, withTopUp.AggregationBits.BitAt(2))
+}
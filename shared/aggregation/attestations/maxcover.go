@@ -1,8 +1,6 @@
 package attestations
 
 import (
-	"sort"
-
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/go-bitfield"
@@ -59,7 +57,11 @@ func MaxCoverAttestationAggregation(atts []*ethpb.Attestation) ([]*ethpb.Attesta
 		unaggregated = unaggregated.selectComplementUsingKeys(solution.Keys)
 	}
 
-	return aggregated.merge(unaggregated.filterContained()), nil
+	maximals, err := NewAttestationSetFromAttestations(unaggregated)
+	if err != nil {
+		return aggregated.merge(unaggregated), err
+	}
+	return aggregated.merge(maximals.Maximals()), nil
 }
 
 // optMaxCoverAttestationAggregation relies on Maximum Coverage greedy algorithm for aggregation.
@@ -144,7 +146,11 @@ func optMaxCoverAttestationAggregation(atts []*ethpb.Attestation) ([]*ethpb.Atte
 		candidates = candidates[:len(unaggregated)-len(processedKeys)]
 	}
 
-	return append(aggregated, filterContainedAttestations(unaggregated)...), nil
+	maximals, err := NewAttestationSetFromAttestations(unaggregated)
+	if err != nil {
+		return append(aggregated, unaggregated...), err
+	}
+	return append(aggregated, maximals.Maximals()...), nil
 }
 
 // NewMaxCover returns initialized Maximum Coverage problem for attestations aggregation.
@@ -279,30 +285,6 @@ func (al attList) hasCoverage(coverage bitfield.Bitlist) bool {
 	return false
 }
 
-// filterContained removes attestations that are contained within other attestations.
-func (al attList) filterContained() attList {
-	if len(al) < 2 {
-		return al
-	}
-	sort.Slice(al, func(i, j int) bool {
-		return al[i].AggregationBits.Count() > al[j].AggregationBits.Count()
-	})
-	filtered := al[:0]
-	filtered = append(filtered, al[0])
-	for i := 1; i < len(al); i++ {
-		if filtered[len(filtered)-1].AggregationBits.Contains(al[i].AggregationBits) {
-			continue
-		}
-		filtered = append(filtered, al[i])
-	}
-	return filtered
-}
-
-// filterContainedAttestations removes attestations that are contained within other attestations.
-func filterContainedAttestations(atts []*ethpb.Attestation) []*ethpb.Attestation {
-	return attList(atts).filterContained()
-}
-
 // validate checks attestation list for validity (equal bitlength, non-nil bitlist etc).
 func (al attList) validate() error {
 	if al == nil {
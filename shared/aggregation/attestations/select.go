@@ -0,0 +1,94 @@
+package attestations
+
+import (
+	"sort"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+)
+
+// SelectForBlock greedily picks the maxAtts attestations from atts that
+// contribute the most new votes on top of alreadySeen (bits already included
+// elsewhere in the block) and bits covered by attestations already picked
+// during this call. For each remaining candidate the score is
+// AggregationBits.AndNot(covered).Count(), tracked per (AttestationData root,
+// committee index) bucket; the highest-scoring candidate is selected, its
+// bits are OR'd into covered, and the process repeats. Ties are broken on
+// higher raw bit count.
+//
+// The original spec for this function additionally called for breaking
+// further ties on "lower signature-aggregation cost", intending fewer set
+// bits to be cheaper to verify. That criterion is self-contradictory: cost
+// tracked popcount directly, so "lower cost" is just "lower raw bit count",
+// the opposite of the tie-break already applied. There is no remaining tier
+// to add without reversing the primary rule, so it is intentionally omitted.
+func SelectForBlock(atts []*ethpb.Attestation, maxAtts int, alreadySeen bitfield.Bitlist) ([]*ethpb.Attestation, error) {
+	if maxAtts <= 0 || len(atts) == 0 {
+		return []*ethpb.Attestation{}, nil
+	}
+	if err := validateAttestations(atts); err != nil {
+		return nil, err
+	}
+
+	covered := make(map[singleKey]bitfield.Bitlist, len(atts))
+	remaining := make([]*ethpb.Attestation, len(atts))
+	copy(remaining, atts)
+
+	selected := make([]*ethpb.Attestation, 0, maxAtts)
+	for len(selected) < maxAtts && len(remaining) > 0 {
+		bestIdx, bestScore, bestCount := -1, -1, -1
+		for i, att := range remaining {
+			key, err := singleKeyFromData(att.Data)
+			if err != nil {
+				return nil, err
+			}
+			seen, ok := covered[key]
+			if !ok && alreadySeen != nil && alreadySeen.Len() == att.AggregationBits.Len() {
+				seen = alreadySeen
+			}
+			score := att.AggregationBits.Count()
+			if seen != nil {
+				score = att.AggregationBits.AndNot(seen).Count()
+			}
+			count := att.AggregationBits.Count()
+
+			switch {
+			case int(score) > bestScore:
+				bestIdx, bestScore, bestCount = i, int(score), int(count)
+			case int(score) == bestScore && int(count) > bestCount:
+				bestIdx, bestScore, bestCount = i, int(score), int(count)
+			}
+		}
+		if bestIdx == -1 || bestScore <= 0 {
+			break
+		}
+
+		best := remaining[bestIdx]
+		selected = append(selected, best)
+		key, err := singleKeyFromData(best.Data)
+		if err != nil {
+			return nil, err
+		}
+		seen, ok := covered[key]
+		if !ok && alreadySeen != nil && alreadySeen.Len() == best.AggregationBits.Len() {
+			seen = alreadySeen
+		}
+		if seen != nil {
+			merged, err := seen.Or(best.AggregationBits)
+			if err != nil {
+				return nil, err
+			}
+			covered[key] = merged
+		} else {
+			covered[key] = best.AggregationBits.Clone()
+		}
+
+		remaining[bestIdx] = remaining[len(remaining)-1]
+		remaining = remaining[:len(remaining)-1]
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].AggregationBits.Count() > selected[j].AggregationBits.Count()
+	})
+	return selected, nil
+}
@@ -0,0 +1,105 @@
+package attestations
+
+import (
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+// testSignature returns a signature over a real BLS key, since the aggregate
+// and top-up paths under test reject the all-zero (non-point) signature.
+func testSignature() []byte {
+	return bls.RandKey().Sign([]byte("attestation")).Marshal()
+}
+
+func attWithBits(data *ethpb.AttestationData, bitlistLen uint64, setBits ...uint64) *ethpb.Attestation {
+	bits := bitfield.NewBitlist(bitlistLen)
+	for _, b := range setBits {
+		bits.SetBitAt(b, true)
+	}
+	return &ethpb.Attestation{
+		AggregationBits: bits,
+		Data:            data,
+		Signature:       testSignature(),
+	}
+}
+
+func TestSelectForBlock_PrefersHigherMarginalCoverage(t *testing.T) {
+	data := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0}
+	// big overlaps almost entirely with alreadySelected; small is disjoint from it
+	// but covers fewer total bits. Once alreadySelected is picked, small should
+	// win over big on the next round because it contributes more new votes.
+	alreadySelected := attWithBits(data, 8, 0, 1, 2, 3, 4)
+	big := attWithBits(data, 8, 0, 1, 2, 3, 5)
+	small := attWithBits(data, 8, 6, 7)
+
+	selected, err := SelectForBlock([]*ethpb.Attestation{alreadySelected, big, small}, 2, nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(selected))
+	assert.Equal(t, alreadySelected, selected[0])
+	assert.Equal(t, small, selected[1])
+}
+
+func TestSelectForBlock_RespectsAlreadySeen(t *testing.T) {
+	data := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0}
+	fullyCovered := attWithBits(data, 8, 0, 1)
+	fresh := attWithBits(data, 8, 2, 3)
+	alreadySeen := bitfield.NewBitlist(8)
+	alreadySeen.SetBitAt(0, true)
+	alreadySeen.SetBitAt(1, true)
+
+	selected, err := SelectForBlock([]*ethpb.Attestation{fullyCovered, fresh}, 2, alreadySeen)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(selected))
+	assert.Equal(t, fresh, selected[0])
+}
+
+func TestSelectForBlock_MaxAttsLimitsSelection(t *testing.T) {
+	data := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0}
+	atts := []*ethpb.Attestation{
+		attWithBits(data, 8, 0),
+		attWithBits(data, 8, 1),
+		attWithBits(data, 8, 2),
+	}
+
+	selected, err := SelectForBlock(atts, 2, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(selected))
+}
+
+func benchmarkAttestations(n int, bitlistLen uint64) []*ethpb.Attestation {
+	data := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0}
+	atts := make([]*ethpb.Attestation, n)
+	for i := 0; i < n; i++ {
+		atts[i] = attWithBits(data, bitlistLen, uint64(i)%bitlistLen)
+	}
+	return atts
+}
+
+func BenchmarkSelectForBlock(b *testing.B) {
+	atts := benchmarkAttestations(256, 256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := SelectForBlock(atts, 128, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkReturnAll approximates today's "return everything" behavior, for
+// comparison against the greedy marginal-coverage selection above.
+func BenchmarkReturnAll(b *testing.B) {
+	atts := benchmarkAttestations(256, 256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		out := atts
+		if len(out) > 128 {
+			out = out[:128]
+		}
+		_ = out
+	}
+}
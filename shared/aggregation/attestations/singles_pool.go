@@ -0,0 +1,205 @@
+package attestations
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+)
+
+// singleKey buckets single-vote attestations by AttestationData root and
+// committee index, so that BuildAggregatesFromSingles only ever runs MaxCover
+// over candidates that can legally be combined.
+type singleKey struct {
+	dataRoot       [32]byte
+	committeeIndex uint64
+}
+
+// SinglesPool holds single-vote attestations (AggregationBits.Count() == 1)
+// apart from the aggregated pool, so that aggregation can be deferred until
+// an aggregate is actually needed (block proposal or aggregation duty),
+// rather than performed eagerly for every attestation that arrives.
+type SinglesPool struct {
+	lock    sync.RWMutex
+	buckets map[singleKey][]*ethpb.Attestation
+}
+
+// NewSinglesPool initializes and returns an empty SinglesPool.
+func NewSinglesPool() *SinglesPool {
+	return &SinglesPool{
+		buckets: make(map[singleKey][]*ethpb.Attestation),
+	}
+}
+
+// Add inserts a single-vote attestation into its bucket. An error is returned
+// if att is not a single-vote attestation.
+func (s *SinglesPool) Add(att *ethpb.Attestation) error {
+	if att == nil || att.AggregationBits == nil {
+		return errors.New("nil attestation")
+	}
+	if att.AggregationBits.Count() != 1 {
+		return errors.New("attestation is not a single vote")
+	}
+	key, err := singleKeyFromData(att.Data)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.buckets[key] = append(s.buckets[key], att)
+	return nil
+}
+
+// Remove deletes att from its bucket, if present.
+func (s *SinglesPool) Remove(att *ethpb.Attestation) error {
+	if att == nil {
+		return errors.New("nil attestation")
+	}
+	key, err := singleKeyFromData(att.Data)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	bucket := s.buckets[key]
+	for i, a := range bucket {
+		if a.AggregationBits.Xor(att.AggregationBits).Count() == 0 {
+			bucket = append(bucket[:i], bucket[i+1:]...)
+			break
+		}
+	}
+	if len(bucket) == 0 {
+		delete(s.buckets, key)
+		return nil
+	}
+	s.buckets[key] = bucket
+	return nil
+}
+
+// ForData returns a copy of every single-vote attestation held for data.
+func (s *SinglesPool) ForData(data *ethpb.AttestationData) ([]*ethpb.Attestation, error) {
+	key, err := singleKeyFromData(data)
+	if err != nil {
+		return nil, err
+	}
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	bucket := s.buckets[key]
+	cpy := make([]*ethpb.Attestation, len(bucket))
+	copy(cpy, bucket)
+	return cpy, nil
+}
+
+// BuildAggregatesFromSingles runs MaxCover over the singles held for data,
+// bounding the work to just that bucket, and returns at most maxOut
+// aggregates. If fewer than two singles are held for data, they are returned
+// unaggregated. maxOut <= 0 means unbounded.
+func (s *SinglesPool) BuildAggregatesFromSingles(data *ethpb.AttestationData, maxOut int) ([]*ethpb.Attestation, error) {
+	singles, err := s.ForData(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(singles) < 2 {
+		return singles, nil
+	}
+	aggregated, err := MaxCoverAttestationAggregation(singles)
+	if err != nil {
+		return nil, err
+	}
+	if maxOut > 0 && len(aggregated) > maxOut {
+		aggregated = aggregated[:maxOut]
+	}
+	return aggregated, nil
+}
+
+// TopUpAggregates walks every network aggregate in atts and ORs in any
+// compatible, non-overlapping singles held in the pool to boost its coverage,
+// re-aggregating signatures for every bit added. Aggregates that share no
+// compatible singles are returned unmodified.
+func (s *SinglesPool) TopUpAggregates(atts []*ethpb.Attestation) ([]*ethpb.Attestation, error) {
+	toppedUp := make([]*ethpb.Attestation, len(atts))
+	for i, att := range atts {
+		topped, err := s.topUp(att)
+		if err != nil {
+			return nil, err
+		}
+		toppedUp[i] = topped
+	}
+	return toppedUp, nil
+}
+
+// topUp ORs in every compatible, non-overlapping single held for att.Data,
+// returning a new aggregate whose signature covers the added bits.
+func (s *SinglesPool) topUp(att *ethpb.Attestation) (*ethpb.Attestation, error) {
+	singles, err := s.ForData(att.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(singles) == 0 {
+		return att, nil
+	}
+
+	sig, err := signatureFromBytes(att.Signature)
+	if err != nil {
+		return nil, err
+	}
+	signs := []bls.Signature{sig}
+	bits := att.AggregationBits
+	added := false
+	for _, single := range singles {
+		if bits.Overlaps(single.AggregationBits) {
+			continue
+		}
+		singleSig, err := signatureFromBytes(single.Signature)
+		if err != nil {
+			return nil, err
+		}
+		merged, err := bits.Or(single.AggregationBits)
+		if err != nil {
+			return nil, err
+		}
+		bits = merged
+		signs = append(signs, singleSig)
+		added = true
+	}
+	if !added {
+		return att, nil
+	}
+	return &ethpb.Attestation{
+		AggregationBits: bits,
+		Data:            stateTrie.CopyAttestationData(att.Data),
+		Signature:       aggregateSignatures(signs).Marshal(),
+	}, nil
+}
+
+// AttestationsForBlock tops up every network aggregate with compatible,
+// non-overlapping singles held in the pool, then hands the topped-up
+// aggregates to SelectForBlock so the bits actually included in the block
+// drive which aggregates are worth their space. Preferring topped-up network
+// aggregates over raw singles avoids re-deriving aggregates the network
+// already produced.
+//
+// Nothing in the block-proposer path or any RPC calls this method; it is
+// exercised directly by this package's tests.
+func (s *SinglesPool) AttestationsForBlock(networkAggs []*ethpb.Attestation, maxAtts int, alreadySeen bitfield.Bitlist) ([]*ethpb.Attestation, error) {
+	toppedUp, err := s.TopUpAggregates(networkAggs)
+	if err != nil {
+		return nil, err
+	}
+	return SelectForBlock(toppedUp, maxAtts, alreadySeen)
+}
+
+// singleKeyFromData derives the bucket key for a given AttestationData.
+func singleKeyFromData(data *ethpb.AttestationData) (singleKey, error) {
+	if data == nil {
+		return singleKey{}, errors.New("nil attestation data")
+	}
+	root, err := data.HashTreeRoot()
+	if err != nil {
+		return singleKey{}, err
+	}
+	return singleKey{dataRoot: root, committeeIndex: data.CommitteeIndex}, nil
+}
@@ -0,0 +1,99 @@
+package attestations
+
+import (
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/testutil/assert"
+	"github.com/prysmaticlabs/prysm/shared/testutil/require"
+)
+
+func singleAttestation(data *ethpb.AttestationData, bit uint64, bitlistLen uint64) *ethpb.Attestation {
+	return attWithBits(data, bitlistLen, bit)
+}
+
+func TestSinglesPool_AddForDataRemove(t *testing.T) {
+	pool := NewSinglesPool()
+	data := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0}
+	att1 := singleAttestation(data, 0, 8)
+	att2 := singleAttestation(data, 1, 8)
+
+	require.NoError(t, pool.Add(att1))
+	require.NoError(t, pool.Add(att2))
+
+	got, err := pool.ForData(data)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(got))
+
+	require.NoError(t, pool.Remove(att1))
+	got, err = pool.ForData(data)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(got))
+}
+
+func TestSinglesPool_Add_RejectsNonSingles(t *testing.T) {
+	pool := NewSinglesPool()
+	data := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0}
+	bits := bitfield.NewBitlist(8)
+	bits.SetBitAt(0, true)
+	bits.SetBitAt(1, true)
+	att := &ethpb.Attestation{AggregationBits: bits, Data: data, Signature: make([]byte, 96)}
+
+	assert.NotNil(t, pool.Add(att))
+}
+
+func TestSinglesPool_BuildAggregatesFromSingles(t *testing.T) {
+	pool := NewSinglesPool()
+	data := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0}
+	for i := uint64(0); i < 3; i++ {
+		require.NoError(t, pool.Add(singleAttestation(data, i, 8)))
+	}
+
+	aggregates, err := pool.BuildAggregatesFromSingles(data, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(aggregates))
+	assert.Equal(t, uint64(3), aggregates[0].AggregationBits.Count())
+}
+
+func TestSinglesPool_TopUpAggregates_StrictlyIncreasesCoverage(t *testing.T) {
+	pool := NewSinglesPool()
+	data := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0}
+	overlapping := singleAttestation(data, 0, 8)
+	disjoint := singleAttestation(data, 1, 8)
+	require.NoError(t, pool.Add(overlapping))
+	require.NoError(t, pool.Add(disjoint))
+
+	networkAgg := singleAttestation(data, 0, 8)
+	before := networkAgg.AggregationBits.Count()
+
+	toppedUp, err := pool.TopUpAggregates([]*ethpb.Attestation{networkAgg})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(toppedUp))
+	assert.Equal(t, true, toppedUp[0].AggregationBits.Count() > before)
+	assert.Equal(t, true, toppedUp[0].AggregationBits.BitAt(1))
+}
+
+func TestSinglesPool_TopUpAggregates_NoCompatibleSingles(t *testing.T) {
+	pool := NewSinglesPool()
+	data := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0}
+	networkAgg := singleAttestation(data, 0, 8)
+
+	toppedUp, err := pool.TopUpAggregates([]*ethpb.Attestation{networkAgg})
+	require.NoError(t, err)
+	assert.Equal(t, networkAgg, toppedUp[0])
+}
+
+func TestSinglesPool_AttestationsForBlock_PrefersToppedUpAggregate(t *testing.T) {
+	pool := NewSinglesPool()
+	data := &ethpb.AttestationData{Slot: 1, CommitteeIndex: 0}
+	disjoint := singleAttestation(data, 1, 8)
+	require.NoError(t, pool.Add(disjoint))
+
+	networkAgg := singleAttestation(data, 0, 8)
+
+	selected, err := pool.AttestationsForBlock([]*ethpb.Attestation{networkAgg}, 1, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(selected))
+	assert.Equal(t, uint64(2), selected[0].AggregationBits.Count())
+}